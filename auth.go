@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const googleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// AuthMode selects how strictly handlePubSub verifies the PubSub push JWT.
+type AuthMode string
+
+const (
+	AuthModeOff      AuthMode = "off"
+	AuthModeOptional AuthMode = "optional"
+	AuthModeRequired AuthMode = "required"
+)
+
+var validIssuers = map[string]bool{
+	"https://accounts.google.com": true,
+	"accounts.google.com":         true,
+}
+
+// jwk is a single JSON Web Key as returned by Google's certs endpoint.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// googleCertsCache fetches and caches a JWKS endpoint, honoring its Cache-Control max-age.
+type googleCertsCache struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+}
+
+// newGoogleCertsCache returns a cache that fetches keys from url on demand.
+func newGoogleCertsCache(url string) *googleCertsCache {
+	return &googleCertsCache{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// get returns the RSA public key for kid, refreshing the cache if it's stale or missing it.
+func (c *googleCertsCache) get(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Before(c.expiresAt) {
+		if key, ok := c.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key, nil
+}
+
+func (c *googleCertsCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching certs: non-200 status %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decoding certs response: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := rsaPublicKeyFromJWK(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	c.keys = keys
+	c.expiresAt = time.Now().Add(cacheTTL(resp.Header.Get("Cache-Control")))
+
+	return nil
+}
+
+// cacheTTL parses the max-age directive from a Cache-Control header, defaulting to one hour.
+func cacheTTL(cacheControl string) time.Duration {
+	const defaultTTL = time.Hour
+
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		seconds, ok := strings.CutPrefix(directive, "max-age=")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(seconds)
+		if err != nil || n <= 0 {
+			continue
+		}
+
+		return time.Duration(n) * time.Second
+	}
+
+	return defaultTTL
+}
+
+func rsaPublicKeyFromJWK(k jwk) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decoding exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+// pubsubClaims are the JWT claims checked on an incoming PubSub push request.
+type pubsubClaims struct {
+	Iss           string `json:"iss"`
+	Aud           string `json:"aud"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Exp           int64  `json:"exp"`
+}
+
+// verifyPubSubJWT validates a Google-signed PubSub push JWT against certs, audience and
+// serviceAccount.
+func verifyPubSubJWT(ctx context.Context, certs *googleCertsCache, token, audience, serviceAccount string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JWT")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("decoding JWT header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fmt.Errorf("parsing JWT header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("unsupported JWT algorithm %q", header.Alg)
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("decoding JWT payload: %w", err)
+	}
+	var claims pubsubClaims
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return fmt.Errorf("parsing JWT claims: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("decoding JWT signature: %w", err)
+	}
+
+	key, err := certs.get(ctx, header.Kid)
+	if err != nil {
+		return fmt.Errorf("fetching signing key: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("invalid JWT signature: %w", err)
+	}
+
+	if claims.Exp != 0 && time.Now().Unix() > claims.Exp {
+		return fmt.Errorf("JWT expired")
+	}
+	if !validIssuers[claims.Iss] {
+		return fmt.Errorf("unexpected issuer %q", claims.Iss)
+	}
+	if claims.Aud != audience {
+		return fmt.Errorf("unexpected audience %q", claims.Aud)
+	}
+	if !claims.EmailVerified {
+		return fmt.Errorf("email not verified")
+	}
+	if claims.Email != serviceAccount {
+		return fmt.Errorf("unexpected service account %q", claims.Email)
+	}
+
+	return nil
+}
+
+// authenticatePubSub enforces authMode against the incoming request's Authorization header.
+func authenticatePubSub(r *http.Request, certs *googleCertsCache, authMode AuthMode, audience, serviceAccount string) error {
+	if authMode == AuthModeOff {
+		return nil
+	}
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		if authMode == AuthModeOptional {
+			return nil
+		}
+		return fmt.Errorf("missing Authorization header")
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authHeader, prefix) {
+		return fmt.Errorf("malformed Authorization header")
+	}
+
+	return verifyPubSubJWT(r.Context(), certs, strings.TrimPrefix(authHeader, prefix), audience, serviceAccount)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testAudience = "https://notifier.example.com/"
+const testServiceAccount = "pubsub-pusher@test-project.iam.gserviceaccount.com"
+
+// newFakeJWKSServer serves key's public half as a JWKS document under kid.
+func newFakeJWKSServer(t *testing.T, key *rsa.PrivateKey, kid string) *httptest.Server {
+	t.Helper()
+
+	set := jwkSet{
+		Keys: []jwk{
+			{
+				Kid: kid,
+				Kty: "RSA",
+				N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(set)
+	}))
+}
+
+// signTestJWT builds a signed RS256 JWT for the given claims.
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims pubsubClaims) string {
+	t.Helper()
+
+	header, err := json.Marshal(struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{Alg: "RS256", Kid: kid})
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validTestClaims() pubsubClaims {
+	return pubsubClaims{
+		Iss:           "https://accounts.google.com",
+		Aud:           testAudience,
+		Email:         testServiceAccount,
+		EmailVerified: true,
+		Exp:           time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyPubSubJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	server := newFakeJWKSServer(t, key, "test-kid")
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		claims  pubsubClaims
+		wantErr bool
+	}{
+		{name: "valid token", claims: validTestClaims()},
+		{
+			name: "wrong audience",
+			claims: func() pubsubClaims {
+				c := validTestClaims()
+				c.Aud = "https://someone-else.example.com/"
+				return c
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			claims: func() pubsubClaims {
+				c := validTestClaims()
+				c.Iss = "https://evil.example.com"
+				return c
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "email not verified",
+			claims: func() pubsubClaims {
+				c := validTestClaims()
+				c.EmailVerified = false
+				return c
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "wrong service account",
+			claims: func() pubsubClaims {
+				c := validTestClaims()
+				c.Email = "someone-else@test-project.iam.gserviceaccount.com"
+				return c
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "expired token",
+			claims: func() pubsubClaims {
+				c := validTestClaims()
+				c.Exp = time.Now().Add(-time.Hour).Unix()
+				return c
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certs := newGoogleCertsCache(server.URL)
+			token := signTestJWT(t, key, "test-kid", tt.claims)
+
+			err := verifyPubSubJWT(context.Background(), certs, token, testAudience, testServiceAccount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyPubSubJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticatePubSub(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	server := newFakeJWKSServer(t, key, "test-kid")
+	defer server.Close()
+
+	certs := newGoogleCertsCache(server.URL)
+	token := signTestJWT(t, key, "test-kid", validTestClaims())
+
+	newRequest := func(authHeader string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name      string
+		authMode  AuthMode
+		authorize string
+		wantErr   bool
+	}{
+		{name: "off mode skips verification", authMode: AuthModeOff, authorize: ""},
+		{name: "optional mode allows missing header", authMode: AuthModeOptional, authorize: ""},
+		{name: "optional mode verifies present header", authMode: AuthModeOptional, authorize: "Bearer " + token},
+		{name: "optional mode rejects bad header", authMode: AuthModeOptional, authorize: "Bearer garbage", wantErr: true},
+		{name: "required mode rejects missing header", authMode: AuthModeRequired, authorize: "", wantErr: true},
+		{name: "required mode accepts valid token", authMode: AuthModeRequired, authorize: "Bearer " + token},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authenticatePubSub(newRequest(tt.authorize), certs, tt.authMode, testAudience, testServiceAccount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authenticatePubSub() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
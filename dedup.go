@@ -0,0 +1,165 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dedupState tracks the occurrences of one dedup key seen so far.
+type dedupState struct {
+	key        string
+	firstSeen  time.Time
+	timestamps []time.Time
+	event      Event
+}
+
+// Deduper wraps a Notifier, dropping or coalescing duplicate events seen within a time window.
+type Deduper struct {
+	next           Notifier
+	dedupWindow    time.Duration
+	coalesceWindow time.Duration
+	maxEntries     int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// NewDeduper wraps next with dedup/coalesce logic. A zero coalesceWindow disables coalescing.
+func NewDeduper(next Notifier, dedupWindow, coalesceWindow time.Duration, maxEntries int) *Deduper {
+	return &Deduper{
+		next:           next,
+		dedupWindow:    dedupWindow,
+		coalesceWindow: coalesceWindow,
+		maxEntries:     maxEntries,
+		order:          list.New(),
+		entries:        make(map[string]*list.Element),
+	}
+}
+
+// Send implements Notifier.
+func (d *Deduper) Send(ctx context.Context, event Event) error {
+	key := dedupKey(event)
+	now := time.Now()
+
+	d.mu.Lock()
+
+	if elem, ok := d.entries[key]; ok {
+		state := elem.Value.(*dedupState)
+		// While coalescing, an entry stays pending until its flush timer removes it, so every
+		// duplicate seen before then is folded into it rather than replacing it unsent.
+		if d.coalesceWindow > 0 || now.Sub(state.firstSeen) < d.dedupWindow {
+			eventsDeduped.Add(1)
+			if d.coalesceWindow > 0 {
+				state.timestamps = append(state.timestamps, now)
+				eventsCoalesced.Add(1)
+			}
+			d.mu.Unlock()
+			return nil
+		}
+
+		d.order.Remove(elem)
+		delete(d.entries, key)
+	}
+
+	state := &dedupState{key: key, firstSeen: now, timestamps: []time.Time{now}, event: event}
+	elem := d.order.PushFront(state)
+	d.entries[key] = elem
+	evicted := d.evictLocked()
+
+	d.mu.Unlock()
+
+	for _, victim := range evicted {
+		slog.Warn("Dedup cache full, flushing pending coalesce entry early", "key", victim.key)
+		if err := d.next.Send(context.Background(), coalescedEvent(victim)); err != nil {
+			slog.Error("Sending force-flushed coalesced notification failed", "error", err)
+		}
+	}
+
+	if d.coalesceWindow > 0 {
+		// Flush on a background context: the HTTP request that triggered this event will
+		// already have completed by the time the coalesce window closes.
+		time.AfterFunc(d.coalesceWindow, func() {
+			d.flush(context.Background(), key)
+		})
+		return nil
+	}
+
+	return d.next.Send(ctx, event)
+}
+
+// evictLocked trims the LRU down to maxEntries, returning any entries still awaiting a coalesce
+// flush so the caller can send them instead of dropping them.
+func (d *Deduper) evictLocked() []*dedupState {
+	var evicted []*dedupState
+
+	for len(d.entries) > d.maxEntries {
+		oldest := d.order.Back()
+		if oldest == nil {
+			break
+		}
+		state := oldest.Value.(*dedupState)
+		d.order.Remove(oldest)
+		delete(d.entries, state.key)
+
+		if d.coalesceWindow > 0 {
+			evicted = append(evicted, state)
+		}
+	}
+
+	return evicted
+}
+
+func (d *Deduper) flush(ctx context.Context, key string) {
+	d.mu.Lock()
+	elem, ok := d.entries[key]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	state := elem.Value.(*dedupState)
+	d.order.Remove(elem)
+	delete(d.entries, key)
+	d.mu.Unlock()
+
+	if err := d.next.Send(ctx, coalescedEvent(state)); err != nil {
+		slog.Error("Sending coalesced notification failed", "error", err)
+	}
+}
+
+// coalescedEvent builds the Event to send for state, appending a repeat count when applicable.
+func coalescedEvent(state *dedupState) Event {
+	event := state.event
+	if len(state.timestamps) > 1 {
+		stamps := make([]string, len(state.timestamps))
+		for i, t := range state.timestamps {
+			stamps[i] = t.Format(time.RFC3339)
+		}
+		event.Data = fmt.Sprintf("%s (x%d)\nSeen at: %s", event.Data, len(state.timestamps), strings.Join(stamps, ", "))
+	}
+
+	return event
+}
+
+// dedupKey hashes the fields that identify duplicate GKE events.
+func dedupKey(event Event) string {
+	dataSum := sha256.Sum256([]byte(event.Data))
+
+	h := sha256.New()
+	h.Write([]byte(event.ProjectId))
+	h.Write([]byte{0})
+	h.Write([]byte(event.ClusterName))
+	h.Write([]byte{0})
+	h.Write([]byte(event.TypeUrl))
+	h.Write([]byte{0})
+	h.Write(dataSum[:])
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotifier records every Event it receives.
+type fakeNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (f *fakeNotifier) Send(ctx context.Context, event Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeNotifier) sent() []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Event(nil), f.events...)
+}
+
+func TestDeduperFlushesEntryEvictedWhileCoalescing(t *testing.T) {
+	fake := &fakeNotifier{}
+	d := NewDeduper(fake, time.Minute, time.Hour, 1)
+
+	first := Event{ProjectId: "p", ClusterName: "c1", TypeUrl: "t", Data: "first"}
+	second := Event{ProjectId: "p", ClusterName: "c2", TypeUrl: "t", Data: "second"}
+
+	if err := d.Send(context.Background(), first); err != nil {
+		t.Fatalf("Send(first): %v", err)
+	}
+
+	// The cache only holds one entry, so this forces eviction of first's still-pending
+	// coalesce entry before its AfterFunc timer ever fires.
+	if err := d.Send(context.Background(), second); err != nil {
+		t.Fatalf("Send(second): %v", err)
+	}
+
+	sent := fake.sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected the evicted entry to be flushed instead of dropped, got %d sends: %+v", len(sent), sent)
+	}
+	if sent[0].Data != "first" {
+		t.Fatalf("expected evicted event data %q, got %q", "first", sent[0].Data)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DiscordNotifier delivers events to a Discord incoming webhook.
+type DiscordNotifier struct {
+	webhookUrl string
+}
+
+// NewDiscordNotifier returns a Notifier that posts events to the given Discord webhook URL.
+func NewDiscordNotifier(webhookUrl string) *DiscordNotifier {
+	return &DiscordNotifier{webhookUrl: webhookUrl}
+}
+
+// newDiscordNotifierFromURL builds a DiscordNotifier from a "discord://" notify URL.
+func newDiscordNotifierFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+
+	return NewDiscordNotifier("https://" + u.Host + u.Path), nil
+}
+
+// Send implements Notifier.
+func (d *DiscordNotifier) Send(ctx context.Context, event Event) error {
+	text, err := renderMessage(event.Message)
+	if err != nil {
+		text = event.Data
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"content": fmt.Sprintf("**%s** on cluster %s (%s)\n%s", eventTypeOf(event.TypeUrl), event.ClusterName, event.ProjectId, text),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status returned from Discord: %d", resp.StatusCode)
+	}
+
+	return nil
+}
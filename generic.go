@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GenericNotifier posts an Event as plain JSON to an arbitrary HTTP(S) endpoint, for sinks that
+// don't need Slack's Block Kit formatting.
+type GenericNotifier struct {
+	url string
+}
+
+// NewGenericNotifier returns a Notifier that POSTs events as JSON to url.
+func NewGenericNotifier(url string) *GenericNotifier {
+	return &GenericNotifier{url: url}
+}
+
+// newGenericNotifierFromURL builds a GenericNotifier from a "generic+<scheme>://" notify URL,
+// e.g. "generic+https://example.com/hook" posts to "https://example.com/hook".
+func newGenericNotifierFromURL(u *url.URL, targetScheme string) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+
+	target := *u
+	target.Scheme = targetScheme
+
+	return NewGenericNotifier(target.String()), nil
+}
+
+// Send implements Notifier.
+func (g *GenericNotifier) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status returned from generic webhook: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewNotifierGenericScheme(t *testing.T) {
+	var received Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n, err := newNotifier("generic+http://" + server.Listener.Addr().String() + "/hook")
+	if err != nil {
+		t.Fatalf("newNotifier: %v", err)
+	}
+
+	event := Event{ClusterName: "test-cluster", ProjectId: "test-project", Data: "hello"}
+	if err := n.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if received.ClusterName != event.ClusterName {
+		t.Fatalf("expected cluster name %q, got %q", event.ClusterName, received.ClusterName)
+	}
+}
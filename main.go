@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -15,6 +14,8 @@ import (
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -22,10 +23,26 @@ const (
 )
 
 var (
-	verbose         = kingpin.Flag("verbose", "Verbose mode.").Short('v').Bool()
-	port            = kingpin.Flag("port", "Port to listen on.").Envar("PORT").Default("8080").String()
-	allowedTypeUrls = kingpin.Flag("allowed-type-urls", "Comma separated allowed type URLs. If empty, all types will be allowed.").Envar("ALLOWED_TYPE_URLS").String()
-	slackWebhookUrl = kingpin.Flag("slack-webhook-url", "Slack webhook URL.").Envar("SLACK_WEBHOOK_URL").Required().String()
+	verbose              = kingpin.Flag("verbose", "Verbose mode.").Short('v').Bool()
+	port                 = kingpin.Flag("port", "Port to listen on.").Envar("PORT").Default("8080").String()
+	allowedTypeUrls      = kingpin.Flag("allowed-type-urls", "Comma separated allowed type URLs. If empty, all types will be allowed.").Envar("ALLOWED_TYPE_URLS").String()
+	notifyUrls           = kingpin.Flag("notify-url", "Notification destination URL: slack://, discord://, teams://, pagerduty:// (each host+path posted to over https), or generic+https://example.com/hook for a plain JSON POST. Repeatable. Can also be set via NOTIFY_URLS (comma separated).").Strings()
+	slackWebhookUrl      = kingpin.Flag("slack-webhook-url", "Deprecated, use --notify-url with a slack:// URL instead. Slack webhook URL.").Envar("SLACK_WEBHOOK_URL").String()
+	pagerWebhookUrl      = kingpin.Flag("pager-webhook-url", "Incident webhook URL (e.g. PagerDuty Events API v2, Squadcast) pageable events are forwarded to.").Envar("PAGER_WEBHOOK_URL").String()
+	severityMapFlag      = kingpin.Flag("severity-map", "Comma separated type=severity overrides for pageable classification, e.g. SecurityBulletinEvent=critical,UpgradeEvent=warning.").Envar("SEVERITY_MAP").String()
+	messageTemplatePath  = kingpin.Flag("message-template", "Path to a Go text/template file used to render the Slack message body. Can also be provided inline via the MESSAGE_TEMPLATE env var.").String()
+	notifyOn             = kingpin.Flag("notify-on", "Comma separated list of event types (e.g. UpgradeAvailableEvent,SecurityBulletinEvent) to send Slack notifications for. If empty, all types are notified.").Envar("NOTIFY_ON").String()
+	authMode             = kingpin.Flag("auth-mode", "PubSub push JWT verification mode.").Default("off").Enum("off", "optional", "required")
+	pubsubAudience       = kingpin.Flag("pubsub-audience", "Expected aud claim on PubSub push JWTs.").Envar("PUBSUB_AUDIENCE").String()
+	pubsubServiceAccount = kingpin.Flag("pubsub-service-account", "Expected email claim on PubSub push JWTs.").Envar("PUBSUB_SERVICE_ACCOUNT").String()
+	dedupWindow          = kingpin.Flag("dedup-window", "Drop a duplicate event (same cluster, type and data) seen again within this window.").Default("5m").Duration()
+	coalesceWindow       = kingpin.Flag("coalesce-window", "If set, batch duplicate events arriving within this window into a single notification instead of sending each one. While set, it takes the place of --dedup-window: once the batched notification is sent, the next duplicate starts a new batch.").Default("0s").Duration()
+	dedupCacheSize       = kingpin.Flag("dedup-cache-size", "Maximum number of distinct event keys tracked by the dedup/coalesce cache.").Default("10000").Int()
+	otlpEndpoint         = kingpin.Flag("otlp-endpoint", "OTLP/HTTP endpoint (host:port) to export traces to. If empty, tracing is a no-op.").Envar("OTLP_ENDPOINT").String()
+
+	notifier    Notifier
+	severityMap map[string]string
+	googleCerts = newGoogleCertsCache(googleCertsURL)
 )
 
 // PubSubMessage contains PubSub message content
@@ -43,28 +60,17 @@ type PubSubMessage struct {
 	Subscription string `json:"subscription"`
 }
 
-// SlackRequestBody contains Slack request body
-type SlackRequestBody struct {
-	Text        string                   `json:"text,omitempty"`
-	Attachments []SlackMessageAttachment `json:"attachments"`
-}
-
-// SlackMessageAttachment contains slack message attachment data
-type SlackMessageAttachment struct {
-	Text     string                 `json:"text,omitempty"`
-	Color    string                 `json:"color,omitempty"`
-	MrkdwnIn []string               `json:"mrkdwn_in,omitempty"`
-	Fields   []SlackAttachmentField `json:"fields"`
-}
+func handlePubSub(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "handlePubSub")
+	defer span.End()
 
-// SlackAttachmentField contains slack attachment field data
-type SlackAttachmentField struct {
-	Short bool   `json:"short"`
-	Title string `json:"title"`
-	Value string `json:"value"`
-}
+	if err := authenticatePubSub(r, googleCerts, AuthMode(*authMode), *pubsubAudience, *pubsubServiceAccount); err != nil {
+		slog.Warn("PubSub push authentication failed", "error", err)
+		pubsubMessagesFiltered.WithLabelValues("auth_failed").Inc()
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-func handlePubSub(w http.ResponseWriter, r *http.Request) {
 	var m PubSubMessage
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -80,14 +86,21 @@ func handlePubSub(w http.ResponseWriter, r *http.Request) {
 
 	slog.Debug("Request", "data", strings.ReplaceAll(string(body), " ", ""))
 
+	span.SetAttributes(
+		attribute.String("type_url", m.Message.Attributes.TypeUrl),
+		attribute.String("cluster_name", m.Message.Attributes.ClusterName),
+	)
+
 	data := string(m.Message.Data)
 	if data == "" {
 		slog.Warn("Received empty data payload, skipping.")
+		pubsubMessagesFiltered.WithLabelValues("empty_data").Inc()
 		return
 	}
 
 	if m.Message.Attributes.TypeUrl == "" {
 		slog.Warn("No type_url in message attributes, skipping Slack notification.")
+		pubsubMessagesFiltered.WithLabelValues("missing_type_url").Inc()
 		return
 	}
 
@@ -108,129 +121,35 @@ func handlePubSub(w http.ResponseWriter, r *http.Request) {
 
 		if !allowedTypeUrlFound {
 			slog.Debug("Received type_url is not on allowed list, skipping", "type_url", m.Message.Attributes.TypeUrl, "allowed list", *allowedTypeUrls)
+			pubsubMessagesFiltered.WithLabelValues("not_allowed").Inc()
 			return
 		}
 	}
 
-	slackRequestBody := SlackRequestBody{
-		Text: data,
-		Attachments: []SlackMessageAttachment{
-			SlackMessageAttachment{
-				Fields: fillMessageFields(m),
-			},
-		},
-	}
+	pubsubMessagesReceived.WithLabelValues(m.Message.Attributes.TypeUrl, m.Message.Attributes.ClusterName).Inc()
 
-	slog.Info("Sending slack notification", "message", data)
-	if err := sendSlackNotificationWithRetry(r.Context(), *slackWebhookUrl, slackRequestBody); err != nil {
-		slog.Error("Sending slack message fail", "error", err)
-		http.Error(w, "Failed to send Slack notification", http.StatusInternalServerError)
+	event := Event{
+		ClusterName:     m.Message.Attributes.ClusterName,
+		ClusterLocation: m.Message.Attributes.ClusterLocation,
+		ProjectId:       m.Message.Attributes.ProjectId,
+		TypeUrl:         m.Message.Attributes.TypeUrl,
+		Data:            data,
+		Message:         m,
 	}
-}
 
-func fillMessageFields(pubSubMessage PubSubMessage) []SlackAttachmentField {
-	fields := []SlackAttachmentField{
-		SlackAttachmentField{
-			Title: "cluster name",
-			Value: pubSubMessage.Message.Attributes.ClusterName,
-			Short: true,
-		},
-		SlackAttachmentField{
-			Title: "cluster location",
-			Value: pubSubMessage.Message.Attributes.ClusterLocation,
-			Short: true,
-		},
-		SlackAttachmentField{
-			Title: "project number",
-			Value: pubSubMessage.Message.Attributes.ProjectId,
-			Short: true,
-		},
+	if err := notifier.Send(ctx, event); err != nil {
+		slog.Error("Sending notification failed", "error", err)
+		http.Error(w, "Failed to send notification", http.StatusInternalServerError)
 	}
 
-	typeUrl := strings.Split(pubSubMessage.Message.Attributes.TypeUrl, ".")
-	eventType := typeUrl[len(typeUrl)-1]
-
-	fields = append(fields, SlackAttachmentField{
-		Title: "event type",
-		Value: eventType,
-		Short: true,
-	})
-
-	return fields
-}
-
-func sendSlackNotificationWithRetry(ctx context.Context, webhookUrl string, slackRequestBody SlackRequestBody) error {
-	const maxAttempts = 3
-	const baseDelay = time.Second
-
-	var lastErr error
-
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		// Attempt to send
-		lastErr = doSendSlackNotification(ctx, webhookUrl, slackRequestBody)
-		if lastErr == nil {
-			// Success on this attempt
-			return nil
-		}
-
-		// If it's not the last attempt, wait before retrying
-		if attempt < maxAttempts {
-			// Log a warning that we're about to retry
-			slog.Warn("Slack send failed, retrying...", "attempt", attempt, "error", lastErr)
-
-			// Exponential backoff: for attempt n, wait 2^(n-1)*baseDelay
-			delay := time.Duration(1<<(attempt-1)) * baseDelay
-			select {
-			case <-time.After(delay):
-				// Continue to next attempt
-			case <-ctx.Done():
-				// If the context got canceled or timed out, stop retrying immediately
-				return ctx.Err()
+	if *pagerWebhookUrl != "" {
+		if severity, pageable := classifyPageable(event, severityMap); pageable {
+			slog.Info("Paging incident webhook", "type_url", event.TypeUrl, "severity", severity)
+			if err := sendPagerAlertWithRetry(ctx, *pagerWebhookUrl, event, severity); err != nil {
+				slog.Error("Sending pager alert failed", "error", err)
 			}
 		}
 	}
-
-	// All attempts failed
-	return fmt.Errorf("Failed to send Slack notification after %d attempts: %w", maxAttempts, lastErr)
-}
-
-// doSendSlackNotification is your existing logic to send Slack messages.
-func doSendSlackNotification(ctx context.Context, webhookUrl string, slackRequestBody SlackRequestBody) error {
-	// Marshal the Slack request body
-	slackBody, err := json.Marshal(slackRequestBody)
-	if err != nil {
-		return err
-	}
-
-	// Create the HTTP request using the provided context
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookUrl, bytes.NewBuffer(slackBody))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("non-200 status returned from Slack: %d", resp.StatusCode)
-	}
-
-	buf := new(bytes.Buffer)
-	if _, err := buf.ReadFrom(resp.Body); err != nil {
-		return fmt.Errorf("failed to read Slack response body: %w", err)
-	}
-
-	if buf.String() != "ok" {
-		return fmt.Errorf("non-ok response returned from Slack: %s", buf.String())
-	}
-
-	return nil
 }
 
 // handleHealthz responds with "OK" indicating the application is running.
@@ -242,6 +161,7 @@ func handleHealthz(w http.ResponseWriter, req *http.Request) {
 func startHTTPServer(ctx context.Context, listenAddress string) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", handleHealthz)
+	mux.Handle("/metrics", promhttp.Handler())
 	mux.HandleFunc("/", handlePubSub)
 
 	server := &http.Server{
@@ -265,6 +185,29 @@ func startHTTPServer(ctx context.Context, listenAddress string) error {
 	return server.ListenAndServe()
 }
 
+// resolveNotifyURLs builds the final list of notify URLs from, in order of precedence, the
+// repeatable --notify-url flag, the NOTIFY_URLS env var (comma separated), and finally the
+// deprecated --slack-webhook-url flag for backward compatibility.
+func resolveNotifyURLs() []string {
+	urls := append([]string{}, *notifyUrls...)
+
+	if len(urls) == 0 {
+		if env := os.Getenv("NOTIFY_URLS"); env != "" {
+			for _, u := range strings.Split(env, ",") {
+				if u = strings.TrimSpace(u); u != "" {
+					urls = append(urls, u)
+				}
+			}
+		}
+	}
+
+	if len(urls) == 0 && *slackWebhookUrl != "" {
+		urls = append(urls, "slack://"+strings.TrimPrefix(*slackWebhookUrl, "https://"))
+	}
+
+	return urls
+}
+
 func main() {
 	var loggingLevel = new(slog.LevelVar)
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: loggingLevel}))
@@ -279,11 +222,52 @@ func main() {
 
 	slog.Info("Program started", "version", ver)
 
+	if *authMode != string(AuthModeOff) && (*pubsubAudience == "" || *pubsubServiceAccount == "") {
+		slog.Error("--pubsub-audience and --pubsub-service-account are required when --auth-mode is not off")
+		os.Exit(1)
+	}
+
+	notifyURLList := resolveNotifyURLs()
+	if len(notifyURLList) == 0 {
+		slog.Error("No notification destination configured, set --notify-url (or the deprecated --slack-webhook-url)")
+		os.Exit(1)
+	}
+
+	n, err := NewNotifiers(notifyURLList)
+	if err != nil {
+		slog.Error("Failed to initialize notifiers", "error", err)
+		os.Exit(1)
+	}
+	notifier = NewDeduper(n, *dedupWindow, *coalesceWindow, *dedupCacheSize)
+	severityMap = parseSeverityMap(*severityMapFlag)
+
+	tmpl, err := loadMessageTemplate(*messageTemplatePath)
+	if err != nil {
+		slog.Error("Failed to load message template", "error", err)
+		os.Exit(1)
+	}
+	messageTemplate = tmpl
+
 	listenAddress := fmt.Sprintf(":%s", *port)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if *otlpEndpoint != "" {
+		shutdownTracer, err := initTracer(ctx, *otlpEndpoint)
+		if err != nil {
+			slog.Error("Failed to initialize OTLP tracing", "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracer(shutdownCtx); err != nil {
+				slog.Error("Error shutting down tracer", "error", err)
+			}
+		}()
+	}
+
 	// Start the HTTP server
 	if err := startHTTPServer(ctx, listenAddress); err != nil && err != http.ErrServerClosed {
 		slog.Error("HTTP server encountered an error", "error", err)
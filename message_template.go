@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// defaultMessageTemplate reproduces the raw event data, matching the pre-templating behavior.
+const defaultMessageTemplate = `{{ printf "%s" .Message.Data }}`
+
+// messageTemplate is the parsed template used to render the body of outgoing Slack messages.
+var messageTemplate *template.Template
+
+// messageTemplateData is what --message-template templates are executed against.
+type messageTemplateData struct {
+	PubSubMessage
+	EventType string
+	Data      interface{}
+}
+
+// loadMessageTemplate parses the template at path, falling back to MESSAGE_TEMPLATE or the
+// built-in default.
+func loadMessageTemplate(path string) (*template.Template, error) {
+	body := defaultMessageTemplate
+
+	switch {
+	case path != "":
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading message template %q: %w", path, err)
+		}
+		body = string(b)
+	case os.Getenv("MESSAGE_TEMPLATE") != "":
+		body = os.Getenv("MESSAGE_TEMPLATE")
+	}
+
+	return template.New("message").Parse(body)
+}
+
+// renderMessage executes messageTemplate against a PubSub message.
+func renderMessage(m PubSubMessage) (string, error) {
+	data := messageTemplateData{
+		PubSubMessage: m,
+		EventType:     eventTypeOf(m.Message.Attributes.TypeUrl),
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(m.Message.Data, &decoded); err == nil {
+		data.Data = decoded
+	}
+
+	var buf bytes.Buffer
+	if err := messageTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"text/template"
+)
+
+func TestRenderMessage(t *testing.T) {
+	orig := messageTemplate
+	defer func() { messageTemplate = orig }()
+
+	tests := []struct {
+		name     string
+		template string
+		message  PubSubMessage
+		want     string
+	}{
+		{
+			name:     "default template reproduces raw data",
+			template: defaultMessageTemplate,
+			message:  pubSubMessageWithData(t, `{"foo":"bar"}`),
+			want:     `{"foo":"bar"}`,
+		},
+		{
+			name:     "template can access decoded JSON fields",
+			template: `{{ .Data.foo }}`,
+			message:  pubSubMessageWithData(t, `{"foo":"bar"}`),
+			want:     "bar",
+		},
+		{
+			name:     "template can access the event type",
+			template: `{{ .EventType }}`,
+			message:  pubSubMessageWithTypeUrl("type.googleapis.com/google.container.v1.SecurityBulletinEvent"),
+			want:     "SecurityBulletinEvent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New("message").Parse(tt.template)
+			if err != nil {
+				t.Fatalf("parsing template: %v", err)
+			}
+			messageTemplate = tmpl
+
+			got, err := renderMessage(tt.message)
+			if err != nil {
+				t.Fatalf("renderMessage: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("renderMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func pubSubMessageWithData(t *testing.T, data string) PubSubMessage {
+	t.Helper()
+	var m PubSubMessage
+	m.Message.Data = []byte(data)
+	return m
+}
+
+func pubSubMessageWithTypeUrl(typeUrl string) PubSubMessage {
+	var m PubSubMessage
+	m.Message.Attributes.TypeUrl = typeUrl
+	return m
+}
+
+func TestLoadMessageTemplateDefault(t *testing.T) {
+	tmpl, err := loadMessageTemplate("")
+	if err != nil {
+		t.Fatalf("loadMessageTemplate: %v", err)
+	}
+
+	orig := messageTemplate
+	messageTemplate = tmpl
+	defer func() { messageTemplate = orig }()
+
+	got, err := renderMessage(pubSubMessageWithData(t, `"hello"`))
+	if err != nil {
+		t.Fatalf("renderMessage: %v", err)
+	}
+	if got != `"hello"` {
+		t.Fatalf("renderMessage() = %q, want %q", got, `"hello"`)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	pubsubMessagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pubsub_messages_received_total",
+		Help: "PubSub push messages received, by event type and cluster.",
+	}, []string{"type_url", "cluster"})
+
+	pubsubMessagesFiltered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pubsub_messages_filtered_total",
+		Help: "PubSub push messages that were not forwarded to a notifier, by reason.",
+	}, []string{"reason"})
+
+	slackSendAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slack_send_attempts_total",
+		Help: "Slack webhook send attempts, by final outcome.",
+	}, []string{"outcome"})
+
+	slackSendDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "slack_send_duration_seconds",
+		Help: "Time spent sending a Slack notification, including retries.",
+	})
+
+	slackRetryTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slack_retry_total",
+		Help: "Slack webhook send retries, by attempt number.",
+	}, []string{"attempt"})
+
+	slackRetryBackoff = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "slack_retry_backoff_seconds",
+		Help: "Backoff delay before the most recent Slack send retry.",
+	})
+
+	eventsDeduped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_deduped_total",
+		Help: "Events dropped because an identical event was already seen within --dedup-window.",
+	})
+
+	eventsCoalesced = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_coalesced_total",
+		Help: "Duplicate events folded into a single coalesced notification.",
+	})
+
+	alertGenerated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "alert_generated_total",
+		Help: "Pager alerts successfully sent to --pager-webhook-url.",
+	})
+
+	alertFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "alert_failed_total",
+		Help: "Pager alerts that failed to send after all retries.",
+	})
+)
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Event represents a single GKE cluster event to be delivered to a notification sink.
+type Event struct {
+	ClusterName     string
+	ClusterLocation string
+	ProjectId       string
+	TypeUrl         string
+	Data            string
+
+	// Message is the original PubSub message this Event was built from.
+	Message PubSubMessage
+}
+
+// Notifier delivers an Event to a notification sink.
+type Notifier interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// eventTypeOf returns the last dot-separated segment of a type_url.
+func eventTypeOf(typeUrl string) string {
+	parts := strings.Split(typeUrl, ".")
+	return parts[len(parts)-1]
+}
+
+// notifierFactories maps a notify-url scheme to a constructor for the matching Notifier.
+// "generic+<scheme>" is handled separately in newNotifier.
+var notifierFactories = map[string]func(*url.URL) (Notifier, error){
+	"slack":     newSlackNotifierFromURL,
+	"discord":   newDiscordNotifierFromURL,
+	"teams":     newTeamsNotifierFromURL,
+	"pagerduty": newPagerDutyNotifierFromURL,
+}
+
+// Multi fans an Event out to a set of Notifiers concurrently and aggregates any errors.
+type Multi struct {
+	notifiers []Notifier
+}
+
+// Send dispatches the event to every wrapped Notifier concurrently.
+func (m *Multi) Send(ctx context.Context, event Event) error {
+	errs := make(chan error, len(m.notifiers))
+
+	for _, n := range m.notifiers {
+		n := n
+		go func() {
+			errs <- n.Send(ctx, event)
+		}()
+	}
+
+	var failures []string
+	for range m.notifiers {
+		if err := <-errs; err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d notifiers failed: %s", len(failures), len(m.notifiers), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// NewNotifiers builds a Multi dispatcher from one or more notify URLs.
+func NewNotifiers(rawURLs []string) (Notifier, error) {
+	if len(rawURLs) == 0 {
+		return nil, fmt.Errorf("no notify URLs configured")
+	}
+
+	notifiers := make([]Notifier, 0, len(rawURLs))
+	for _, rawURL := range rawURLs {
+		n, err := newNotifier(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notify URL %q: %w", rawURL, err)
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	return &Multi{notifiers: notifiers}, nil
+}
+
+// newNotifier parses a notify URL and builds the Notifier registered for its scheme.
+func newNotifier(rawURL string) (Notifier, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("missing scheme")
+	}
+
+	if targetScheme, ok := strings.CutPrefix(u.Scheme, "generic+"); ok {
+		if targetScheme == "" {
+			return nil, fmt.Errorf("missing target scheme after generic+")
+		}
+		return newGenericNotifierFromURL(u, targetScheme)
+	}
+
+	factory, ok := notifierFactories[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	return factory(u)
+}
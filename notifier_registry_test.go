@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewNotifierDiscordScheme(t *testing.T) {
+	n, err := newNotifier("discord://discord.com/api/webhooks/123/abc")
+	if err != nil {
+		t.Fatalf("newNotifier: %v", err)
+	}
+
+	discord, ok := n.(*DiscordNotifier)
+	if !ok {
+		t.Fatalf("expected *DiscordNotifier, got %T", n)
+	}
+	if want := "https://discord.com/api/webhooks/123/abc"; discord.webhookUrl != want {
+		t.Fatalf("webhookUrl = %q, want %q", discord.webhookUrl, want)
+	}
+}
+
+func TestNewNotifierTeamsScheme(t *testing.T) {
+	n, err := newNotifier("teams://outlook.office.com/webhookb2/123")
+	if err != nil {
+		t.Fatalf("newNotifier: %v", err)
+	}
+
+	teams, ok := n.(*TeamsNotifier)
+	if !ok {
+		t.Fatalf("expected *TeamsNotifier, got %T", n)
+	}
+	if want := "https://outlook.office.com/webhookb2/123"; teams.webhookUrl != want {
+		t.Fatalf("webhookUrl = %q, want %q", teams.webhookUrl, want)
+	}
+}
+
+func TestNewNotifierPagerDutySchemeSkipsNonPageable(t *testing.T) {
+	n, err := newNotifier("pagerduty://events.pagerduty.com/v2/enqueue")
+	if err != nil {
+		t.Fatalf("newNotifier: %v", err)
+	}
+
+	pd, ok := n.(*PagerDutyNotifier)
+	if !ok {
+		t.Fatalf("expected *PagerDutyNotifier, got %T", n)
+	}
+	if want := "https://events.pagerduty.com/v2/enqueue"; pd.webhookUrl != want {
+		t.Fatalf("webhookUrl = %q, want %q", pd.webhookUrl, want)
+	}
+
+	event := Event{ClusterName: "test-cluster", TypeUrl: "type.googleapis.com/google.container.v1.UpgradeAvailableEvent"}
+	if err := pd.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send on a non-pageable event should be skipped, not attempted: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultSeverityMap maps an event type to the severity it's paged with by default.
+var defaultSeverityMap = map[string]string{
+	"SecurityBulletinEvent": "critical",
+	"UpgradeEvent":          "warning",
+}
+
+// PagerEvent is the payload posted to --pager-webhook-url.
+type PagerEvent struct {
+	Message     string    `json:"message"`
+	Description string    `json:"description"`
+	Tags        PagerTags `json:"tags"`
+	Status      string    `json:"status"`
+	EventId     string    `json:"event_id"`
+}
+
+// PagerTags carries the dimensions on a PagerEvent.
+type PagerTags struct {
+	Severity string `json:"severity"`
+	Cluster  string `json:"cluster"`
+	Project  string `json:"project"`
+}
+
+// parseSeverityMap parses a comma separated "type=severity" list.
+func parseSeverityMap(raw string) map[string]string {
+	severityMap := make(map[string]string)
+
+	if raw == "" {
+		return severityMap
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			slog.Warn("Ignoring malformed severity-map entry", "entry", pair)
+			continue
+		}
+
+		severityMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return severityMap
+}
+
+// upgradeEventPayload is the subset of the decoded UpgradeEvent payload we care about.
+type upgradeEventPayload struct {
+	ResourceType string `json:"resourceType"`
+}
+
+// isControlPlaneUpgrade reports whether an UpgradeEvent targets the control plane.
+func isControlPlaneUpgrade(data string) bool {
+	var payload upgradeEventPayload
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return false
+	}
+
+	return strings.EqualFold(payload.ResourceType, "MASTER")
+}
+
+// classifyPageable decides whether an Event should be paged, and with what severity.
+func classifyPageable(event Event, severityMap map[string]string) (severity string, pageable bool) {
+	eventType := eventTypeOf(event.TypeUrl)
+
+	switch {
+	case eventType == "SecurityBulletinEvent":
+		pageable = true
+	case eventType == "UpgradeEvent" && isControlPlaneUpgrade(event.Data):
+		pageable = true
+	default:
+		_, overridden := severityMap[eventType]
+		pageable = overridden
+	}
+
+	if !pageable {
+		return "", false
+	}
+
+	if severity, ok := severityMap[eventType]; ok {
+		return severity, true
+	}
+	if severity, ok := defaultSeverityMap[eventType]; ok {
+		return severity, true
+	}
+
+	return "warning", true
+}
+
+// sendPagerAlertWithRetry posts a pageable Event to the incident webhook, retrying on failure.
+func sendPagerAlertWithRetry(ctx context.Context, webhookUrl string, event Event, severity string) error {
+	const maxAttempts = 3
+	const baseDelay = time.Second
+
+	pagerEvent := PagerEvent{
+		Message:     fmt.Sprintf("%s on cluster %s", eventTypeOf(event.TypeUrl), event.ClusterName),
+		Description: event.Data,
+		Tags: PagerTags{
+			Severity: severity,
+			Cluster:  event.ClusterName,
+			Project:  event.ProjectId,
+		},
+		Status:  "trigger",
+		EventId: newEventID(),
+	}
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = doSendPagerAlert(ctx, webhookUrl, pagerEvent)
+		if lastErr == nil {
+			alertGenerated.Add(1)
+			return nil
+		}
+
+		if attempt < maxAttempts {
+			slog.Warn("Pager alert send failed, retrying...", "attempt", attempt, "error", lastErr)
+
+			delay := time.Duration(1<<(attempt-1)) * baseDelay
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				alertFailed.Add(1)
+				return ctx.Err()
+			}
+		}
+	}
+
+	alertFailed.Add(1)
+	return fmt.Errorf("failed to send pager alert after %d attempts: %w", maxAttempts, lastErr)
+}
+
+func doSendPagerAlert(ctx context.Context, webhookUrl string, pagerEvent PagerEvent) error {
+	body, err := json.Marshal(pagerEvent)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status returned from pager webhook: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// newEventID returns a random RFC 4122 version 4 UUID string.
+func newEventID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,127 @@
+package main
+
+import "testing"
+
+func TestParseSeverityMap(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want map[string]string
+	}{
+		{name: "empty", raw: "", want: map[string]string{}},
+		{
+			name: "single pair",
+			raw:  "SecurityBulletinEvent=critical",
+			want: map[string]string{"SecurityBulletinEvent": "critical"},
+		},
+		{
+			name: "multiple pairs with whitespace",
+			raw:  "SecurityBulletinEvent=critical, UpgradeEvent = warning",
+			want: map[string]string{"SecurityBulletinEvent": "critical", "UpgradeEvent": "warning"},
+		},
+		{
+			name: "malformed entry ignored",
+			raw:  "SecurityBulletinEvent=critical,garbage,UpgradeEvent=warning",
+			want: map[string]string{"SecurityBulletinEvent": "critical", "UpgradeEvent": "warning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSeverityMap(tt.raw)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseSeverityMap(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Fatalf("parseSeverityMap(%q)[%q] = %q, want %q", tt.raw, k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestIsControlPlaneUpgrade(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{name: "master upgrade", data: `{"resourceType":"MASTER"}`, want: true},
+		{name: "case insensitive", data: `{"resourceType":"master"}`, want: true},
+		{name: "node pool upgrade", data: `{"resourceType":"NODE_POOL"}`, want: false},
+		{name: "malformed data", data: `not json`, want: false},
+		{name: "missing field", data: `{}`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isControlPlaneUpgrade(tt.data); got != tt.want {
+				t.Fatalf("isControlPlaneUpgrade(%q) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyPageable(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        Event
+		severityMap  map[string]string
+		wantSeverity string
+		wantPageable bool
+	}{
+		{
+			name:         "security bulletin always pageable",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.SecurityBulletinEvent"},
+			severityMap:  map[string]string{},
+			wantSeverity: "critical",
+			wantPageable: true,
+		},
+		{
+			name:         "control plane upgrade is pageable",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.UpgradeEvent", Data: `{"resourceType":"MASTER"}`},
+			severityMap:  map[string]string{},
+			wantSeverity: "warning",
+			wantPageable: true,
+		},
+		{
+			name:         "node pool upgrade is not pageable",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.UpgradeEvent", Data: `{"resourceType":"NODE_POOL"}`},
+			severityMap:  map[string]string{},
+			wantPageable: false,
+		},
+		{
+			name:         "unrelated event type is not pageable by default",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.UpgradeAvailableEvent"},
+			severityMap:  map[string]string{},
+			wantPageable: false,
+		},
+		{
+			name:         "severity-map override makes an otherwise silent type pageable",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.UpgradeAvailableEvent"},
+			severityMap:  map[string]string{"UpgradeAvailableEvent": "info"},
+			wantSeverity: "info",
+			wantPageable: true,
+		},
+		{
+			name:         "severity-map override replaces the default severity",
+			event:        Event{TypeUrl: "type.googleapis.com/google.container.v1.SecurityBulletinEvent"},
+			severityMap:  map[string]string{"SecurityBulletinEvent": "sev1"},
+			wantSeverity: "sev1",
+			wantPageable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, pageable := classifyPageable(tt.event, tt.severityMap)
+			if pageable != tt.wantPageable {
+				t.Fatalf("classifyPageable() pageable = %v, want %v", pageable, tt.wantPageable)
+			}
+			if pageable && severity != tt.wantSeverity {
+				t.Fatalf("classifyPageable() severity = %q, want %q", severity, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// PagerDutyNotifier delivers pageable events to an incident webhook (PagerDuty Events API v2,
+// Squadcast, ...).
+type PagerDutyNotifier struct {
+	webhookUrl string
+}
+
+// NewPagerDutyNotifier returns a Notifier that posts pageable events to the given webhook URL.
+func NewPagerDutyNotifier(webhookUrl string) *PagerDutyNotifier {
+	return &PagerDutyNotifier{webhookUrl: webhookUrl}
+}
+
+// newPagerDutyNotifierFromURL builds a PagerDutyNotifier from a "pagerduty://" notify URL.
+func newPagerDutyNotifierFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+
+	return NewPagerDutyNotifier("https://" + u.Host + u.Path), nil
+}
+
+// Send implements Notifier, silently skipping events classifyPageable doesn't consider pageable.
+func (p *PagerDutyNotifier) Send(ctx context.Context, event Event) error {
+	severity, pageable := classifyPageable(event, severityMap)
+	if !pageable {
+		return nil
+	}
+
+	return sendPagerAlertWithRetry(ctx, p.webhookUrl, event, severity)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlackRequestBody contains Slack request body
+type SlackRequestBody struct {
+	Attachments []SlackAttachment `json:"attachments"`
+}
+
+// SlackAttachment wraps a set of Block Kit blocks with Slack's color bar.
+type SlackAttachment struct {
+	Color  string       `json:"color,omitempty"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is a single Block Kit block (section, context, actions, ...).
+type SlackBlock map[string]interface{}
+
+// sectionBlock renders a block of mrkdwn text.
+func sectionBlock(markdown string) SlackBlock {
+	return SlackBlock{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": markdown},
+	}
+}
+
+// contextBlock renders a row of small mrkdwn text elements below a section.
+func contextBlock(markdownElements []string) SlackBlock {
+	elements := make([]map[string]string, 0, len(markdownElements))
+	for _, e := range markdownElements {
+		elements = append(elements, map[string]string{"type": "mrkdwn", "text": e})
+	}
+
+	return SlackBlock{"type": "context", "elements": elements}
+}
+
+// actionsBlock renders a row of buttons, here used for the GKE console deep link.
+func actionsBlock(buttonText, url string) SlackBlock {
+	return SlackBlock{
+		"type": "actions",
+		"elements": []map[string]interface{}{
+			{
+				"type": "button",
+				"text": map[string]string{"type": "plain_text", "text": buttonText},
+				"url":  url,
+			},
+		},
+	}
+}
+
+// eventStyle is the color and icon a Slack message is rendered with for a given event type.
+type eventStyle struct {
+	Color string
+	Icon  string
+}
+
+// eventStyles maps an event type to its Slack presentation.
+var eventStyles = map[string]eventStyle{
+	"UpgradeEvent":          {Color: "warning", Icon: ":arrow_up:"},
+	"UpgradeAvailableEvent": {Color: "good", Icon: ":bulb:"},
+	"SecurityBulletinEvent": {Color: "danger", Icon: ":rotating_light:"},
+}
+
+var defaultEventStyle = eventStyle{Color: "#439FE0", Icon: ":information_source:"}
+
+// gkeConsoleURL builds a deep link to the GKE console page for a cluster.
+func gkeConsoleURL(location, name, projectId string) string {
+	return fmt.Sprintf(
+		"https://console.cloud.google.com/kubernetes/clusters/details/%s/%s?project=%s",
+		url.PathEscape(location), url.PathEscape(name), url.QueryEscape(projectId),
+	)
+}
+
+// SlackNotifier delivers events to a Slack incoming webhook.
+type SlackNotifier struct {
+	webhookUrl string
+}
+
+// NewSlackNotifier returns a Notifier that posts events to the given Slack webhook URL.
+func NewSlackNotifier(webhookUrl string) *SlackNotifier {
+	return &SlackNotifier{webhookUrl: webhookUrl}
+}
+
+// newSlackNotifierFromURL builds a SlackNotifier from a "slack://" notify URL.
+func newSlackNotifierFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+
+	return NewSlackNotifier("https://" + u.Host + u.Path), nil
+}
+
+// Send implements Notifier.
+func (s *SlackNotifier) Send(ctx context.Context, event Event) error {
+	eventType := eventTypeOf(event.TypeUrl)
+
+	if *notifyOn != "" && !notifyOnList(*notifyOn).contains(eventType) {
+		slog.Debug("Event type not in --notify-on list, skipping Slack notification", "type_url", event.TypeUrl)
+		return nil
+	}
+
+	style, ok := eventStyles[eventType]
+	if !ok {
+		style = defaultEventStyle
+	}
+
+	text, err := renderMessage(event.Message)
+	if err != nil {
+		slog.Error("Failed to render message template, falling back to raw data", "error", err)
+		text = event.Data
+	}
+
+	blocks := []SlackBlock{
+		sectionBlock(fmt.Sprintf("%s %s", style.Icon, text)),
+		contextBlock([]string{
+			fmt.Sprintf("*cluster:* %s", event.ClusterName),
+			fmt.Sprintf("*location:* %s", event.ClusterLocation),
+			fmt.Sprintf("*project:* %s", event.ProjectId),
+			fmt.Sprintf("*event type:* %s", eventType),
+		}),
+		actionsBlock("View in GKE console", gkeConsoleURL(event.ClusterLocation, event.ClusterName, event.ProjectId)),
+	}
+
+	slackRequestBody := SlackRequestBody{
+		Attachments: []SlackAttachment{
+			{Color: style.Color, Blocks: blocks},
+		},
+	}
+
+	slog.Info("Sending slack notification", "message", event.Data)
+	return sendSlackNotificationWithRetry(ctx, s.webhookUrl, slackRequestBody)
+}
+
+// notifyOnList is a comma separated list of event types from --notify-on.
+type notifyOnList string
+
+func (l notifyOnList) contains(eventType string) bool {
+	for _, t := range strings.Split(string(l), ",") {
+		if strings.TrimSpace(t) == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sendSlackNotificationWithRetry(ctx context.Context, webhookUrl string, slackRequestBody SlackRequestBody) error {
+	const maxAttempts = 3
+	const baseDelay = time.Second
+
+	start := time.Now()
+	defer func() { slackSendDuration.Observe(time.Since(start).Seconds()) }()
+
+	var lastErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		// Attempt to send
+		lastErr = doSendSlackNotification(ctx, webhookUrl, slackRequestBody)
+		if lastErr == nil {
+			// Success on this attempt
+			slackSendAttempts.WithLabelValues("success").Inc()
+			trace.SpanFromContext(ctx).SetAttributes(attribute.Int("retry_count", attempt-1))
+			return nil
+		}
+
+		// If it's not the last attempt, wait before retrying
+		if attempt < maxAttempts {
+			// Log a warning that we're about to retry
+			slog.Warn("Slack send failed, retrying...", "attempt", attempt, "error", lastErr)
+			slackRetryTotal.WithLabelValues(strconv.Itoa(attempt)).Inc()
+
+			// Exponential backoff: for attempt n, wait 2^(n-1)*baseDelay
+			delay := time.Duration(1<<(attempt-1)) * baseDelay
+			slackRetryBackoff.Set(delay.Seconds())
+			select {
+			case <-time.After(delay):
+				// Continue to next attempt
+			case <-ctx.Done():
+				// If the context got canceled or timed out, stop retrying immediately
+				slackSendAttempts.WithLabelValues("canceled").Inc()
+				return ctx.Err()
+			}
+		}
+	}
+
+	// All attempts failed
+	slackSendAttempts.WithLabelValues("failure").Inc()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("retry_count", maxAttempts-1))
+	return fmt.Errorf("Failed to send Slack notification after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// doSendSlackNotification is your existing logic to send Slack messages.
+func doSendSlackNotification(ctx context.Context, webhookUrl string, slackRequestBody SlackRequestBody) error {
+	// Marshal the Slack request body
+	slackBody, err := json.Marshal(slackRequestBody)
+	if err != nil {
+		return err
+	}
+
+	// Create the HTTP request using the provided context
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookUrl, bytes.NewBuffer(slackBody))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 status returned from Slack: %d", resp.StatusCode)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read Slack response body: %w", err)
+	}
+
+	if buf.String() != "ok" {
+		return fmt.Errorf("non-ok response returned from Slack: %s", buf.String())
+	}
+
+	return nil
+}
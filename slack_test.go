@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestNotifyOnListContains(t *testing.T) {
+	tests := []struct {
+		name      string
+		list      notifyOnList
+		eventType string
+		want      bool
+	}{
+		{name: "exact match", list: "UpgradeEvent,SecurityBulletinEvent", eventType: "SecurityBulletinEvent", want: true},
+		{name: "no match", list: "UpgradeEvent,SecurityBulletinEvent", eventType: "UpgradeAvailableEvent", want: false},
+		{name: "whitespace around entries", list: "UpgradeEvent, SecurityBulletinEvent ", eventType: "SecurityBulletinEvent", want: true},
+		{name: "single entry match", list: "SecurityBulletinEvent", eventType: "SecurityBulletinEvent", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.list.contains(tt.eventType); got != tt.want {
+				t.Fatalf("contains(%q) on %q = %v, want %v", tt.eventType, tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGkeConsoleURL(t *testing.T) {
+	got := gkeConsoleURL("us-central1", "my cluster", "my project")
+	want := "https://console.cloud.google.com/kubernetes/clusters/details/us-central1/my%20cluster?project=my+project"
+	if got != want {
+		t.Fatalf("gkeConsoleURL() = %q, want %q", got, want)
+	}
+}
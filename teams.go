@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TeamsNotifier delivers events to a Microsoft Teams incoming webhook.
+type TeamsNotifier struct {
+	webhookUrl string
+}
+
+// NewTeamsNotifier returns a Notifier that posts events to the given Teams webhook URL.
+func NewTeamsNotifier(webhookUrl string) *TeamsNotifier {
+	return &TeamsNotifier{webhookUrl: webhookUrl}
+}
+
+// newTeamsNotifierFromURL builds a TeamsNotifier from a "teams://" notify URL.
+func newTeamsNotifierFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host")
+	}
+
+	return NewTeamsNotifier("https://" + u.Host + u.Path), nil
+}
+
+// Send implements Notifier.
+func (t *TeamsNotifier) Send(ctx context.Context, event Event) error {
+	text, err := renderMessage(event.Message)
+	if err != nil {
+		text = event.Data
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"@type":    "MessageCard",
+		"@context": "http://schema.org/extensions",
+		"title":    fmt.Sprintf("%s on cluster %s", eventTypeOf(event.TypeUrl), event.ClusterName),
+		"text":     text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhookUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status returned from Teams: %d", resp.StatusCode)
+	}
+
+	return nil
+}